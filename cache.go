@@ -0,0 +1,231 @@
+package templates
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+)
+
+// WithRenderCache enables memoizing RenderCached output by (templateName,
+// key) in an in-memory LRU capped at maxBytes of rendered bytes. Entries for
+// a View are evicted as soon as it is recompiled, e.g. by auto-reload or
+// Watch.
+func WithRenderCache(maxBytes int) Option {
+	return func(r *ViewRenderer) {
+		r.cache = newRenderCache(maxBytes)
+	}
+}
+
+// RenderCached renders name into a buffer, computes a strong ETag from the
+// SHA-256 of the rendered bytes, and sets ETag and Last-Modified (the
+// newest mtime among the layout, includes, and page files) on the response.
+// It returns 304 Not Modified when the request's If-None-Match or
+// If-Modified-Since headers are satisfied. When WithRenderCache is
+// configured, the rendered bytes are memoized by (templateName, key) so
+// repeat requests skip template execution entirely.
+func (t *ViewRenderer) RenderCached(c Context, code int, name string, key string, data any) error {
+	tmpl, err := t.lookupTemplate(name)
+	if err != nil {
+		t.logger.ErrorCtx(c.Request().Context(), "failed to load template", err, map[string]any{"name": name})
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	templateName := path.Base(tmpl.name)
+
+	var entry *cacheEntry
+	if t.cache != nil {
+		entry, _ = t.cache.get(templateName, key)
+	}
+
+	if entry == nil {
+		entry, err = t.renderCacheEntry(tmpl, templateName, key, data)
+		if err != nil {
+			t.logger.ErrorCtx(c.Request().Context(), "failed to render cached template", err, map[string]any{"name": tmpl.name})
+			return err
+		}
+
+		if t.cache != nil {
+			t.cache.set(entry)
+		}
+	}
+
+	res := c.Response()
+	res.Header().Set("ETag", entry.etag)
+	res.Header().Set("Last-Modified", entry.lastModified.UTC().Format(http.TimeFormat))
+
+	// the validators above are set regardless, per RFC 7232, so a client
+	// revalidating via If-None-Match/If-Modified-Since still gets a
+	// refreshed ETag/Last-Modified on a 304
+	if notModified(c.Request(), entry.etag, entry.lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	res.WriteHeader(code)
+	_, err = res.Write(entry.body)
+
+	return err
+}
+
+func (t *ViewRenderer) renderCacheEntry(tmpl *View, templateName, key string, data any) (*cacheEntry, error) {
+	lastModified, err := t.lastModified(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat template %s: %w", tmpl.name, err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.template().Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", tmpl.name, err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+
+	return &cacheEntry{
+		templateName: templateName,
+		key:          key,
+		body:         buf.Bytes(),
+		etag:         fmt.Sprintf(`"%x"`, sum),
+		lastModified: lastModified,
+	}, nil
+}
+
+// lastModified returns the newest mtime among tmpl's layout, includes, and
+// page files.
+func (t *ViewRenderer) lastModified(tmpl *View) (time.Time, error) {
+	var latest time.Time
+
+	patterns := make([]string, 0, 3)
+	if tmpl.layout != "" {
+		patterns = append(patterns, tmpl.layout)
+	}
+	if tmpl.includes != "" {
+		patterns = append(patterns, tmpl.includes)
+	}
+	patterns = append(patterns, tmpl.name)
+
+	filenames, err := readFileNames(t.fsys, patterns...)
+	if err != nil {
+		return latest, err
+	}
+
+	for _, f := range filenames {
+		info, err := fs.Stat(t.fsys, f)
+		if err != nil {
+			return latest, fmt.Errorf("failed to stat %s: %w", f, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// notModified reports whether req's conditional headers are satisfied by
+// etag and lastModified, per If-None-Match taking precedence over
+// If-Modified-Since as described in RFC 7232.
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		return match == etag || match == "*"
+	}
+
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" {
+		if since, err := http.ParseTime(ims); err == nil {
+			return !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+
+	return false
+}
+
+// cacheEntry is a memoized RenderCached result.
+type cacheEntry struct {
+	templateName string
+	key          string
+	body         []byte
+	etag         string
+	lastModified time.Time
+}
+
+// renderCache is a byte-size-bounded LRU of cacheEntry, keyed by
+// (templateName, key).
+type renderCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	curBytes int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newRenderCache(maxBytes int) *renderCache {
+	return &renderCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func renderCacheKey(templateName, key string) string {
+	return templateName + "\x00" + key
+}
+
+func (c *renderCache) get(templateName, key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[renderCacheKey(templateName, key)]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+
+	return el.Value.(*cacheEntry), true
+}
+
+func (c *renderCache) set(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := renderCacheKey(entry.templateName, entry.key)
+
+	if el, ok := c.items[k]; ok {
+		c.curBytes -= len(el.Value.(*cacheEntry).body)
+		el.Value = entry
+		c.ll.MoveToFront(el)
+	} else {
+		c.items[k] = c.ll.PushFront(entry)
+	}
+
+	c.curBytes += len(entry.body)
+
+	for c.curBytes > c.maxBytes && c.ll.Len() > 0 {
+		oldest := c.ll.Back()
+		old := oldest.Value.(*cacheEntry)
+		c.ll.Remove(oldest)
+		delete(c.items, renderCacheKey(old.templateName, old.key))
+		c.curBytes -= len(old.body)
+	}
+}
+
+// invalidate removes every cached entry for templateName.
+func (c *renderCache) invalidate(templateName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, el := range c.items {
+		entry := el.Value.(*cacheEntry)
+		if entry.templateName != templateName {
+			continue
+		}
+
+		c.ll.Remove(el)
+		delete(c.items, k)
+		c.curBytes -= len(entry.body)
+	}
+}
@@ -0,0 +1,166 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+)
+
+// templateErrorPattern matches the standard "template: name:line:col:"
+// prefix that html/template uses for both parse and execute errors. It's a
+// fallback for errors parseTemplateError doesn't recognize as a typed
+// *template.Error or texttemplate.ExecError, so it isn't anchored: the
+// bundled engines wrap the library's error, and compileTemplate wraps the
+// engine's, with fmt.Errorf("...: %w", err), which prefixes err.Error() and
+// would defeat a leading "^".
+var templateErrorPattern = regexp.MustCompile(`template:\s*([^:]+):(\d+)(?::(\d+))?:`)
+
+// templateErrorLocation is the file and position extracted from a template
+// parse or execute error.
+type templateErrorLocation struct {
+	name string
+	line int
+	col  int
+}
+
+// parseTemplateError extracts the offending file name and line/column from a
+// html/template parse or execute error. It first unwraps err looking for the
+// typed errors html/template returns - *template.Error for the escaping
+// errors it reports when a template is first executed, and
+// texttemplate.ExecError for execute errors, since html/template's Template
+// delegates execution to the underlying text/template - which survive being
+// wrapped by compileTemplate and the engine's own error wrapping. Errors that
+// don't unwrap to either type (parse errors, which html/template returns
+// untyped, and errors from engines that don't use html/template) fall back to
+// parsing the standard "template: name:line:col:" prefix out of err.Error().
+func parseTemplateError(err error) (templateErrorLocation, bool) {
+	var perr *template.Error
+	if errors.As(err, &perr) {
+		return templateErrorLocation{name: perr.Name, line: perr.Line}, true
+	}
+
+	var eerr texttemplate.ExecError
+	if errors.As(err, &eerr) {
+		loc := templateErrorLocation{name: eerr.Name}
+		if m := templateErrorPattern.FindStringSubmatch(eerr.Error()); m != nil {
+			loc.line, _ = strconv.Atoi(m[2])
+			if m[3] != "" {
+				loc.col, _ = strconv.Atoi(m[3])
+			}
+		}
+		return loc, true
+	}
+
+	m := templateErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return templateErrorLocation{}, false
+	}
+
+	loc := templateErrorLocation{name: m[1]}
+	loc.line, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		loc.col, _ = strconv.Atoi(m[3])
+	}
+
+	return loc, true
+}
+
+// devErrorPage renders a self-contained HTML page describing a template
+// parse or execute error, with ~10 lines of source context around the
+// offending line and the layout/includes/page chain that produced it. It is
+// used in place of the normal error response when WithDevErrors(true) is
+// set, to make failures visible in the browser during development.
+func (t *ViewRenderer) devErrorPage(tmpl *View, stage string, err error) []byte {
+	buf := new(bytes.Buffer)
+
+	buf.WriteString(devErrorPageHeader)
+	fmt.Fprintf(buf, "<h1>template %s error</h1>\n", html.EscapeString(stage))
+	fmt.Fprintf(buf, "<p class=\"message\">%s</p>\n", html.EscapeString(err.Error()))
+
+	if loc, ok := parseTemplateError(err); ok {
+		if ctx, cerr := t.sourceContext(tmpl, loc); cerr == nil {
+			fmt.Fprintf(buf, "<h2>%s</h2>\n<pre class=\"source\">%s</pre>\n", html.EscapeString(loc.name), ctx)
+		}
+	}
+
+	buf.WriteString("<h2>files</h2>\n<ul class=\"chain\">\n")
+	for _, f := range []string{tmpl.layout, tmpl.includes, tmpl.name} {
+		if f == "" {
+			continue
+		}
+		fmt.Fprintf(buf, "<li>%s</li>\n", html.EscapeString(f))
+	}
+	buf.WriteString("</ul>\n")
+	buf.WriteString(devErrorPageFooter)
+
+	return buf.Bytes()
+}
+
+// sourceContext reads loc's file back from the renderer's fs.FS and returns
+// ~10 lines of HTML-escaped source centred on line, with the offending line
+// wrapped so it can be highlighted. loc.name is the base name html/template
+// uses internally, which may not be loc's actual path in fsys (e.g. a page
+// under pages/ or an include under includes/), so it's resolved against
+// tmpl's recorded source paths first.
+func (t *ViewRenderer) sourceContext(tmpl *View, loc templateErrorLocation) (string, error) {
+	name := loc.name
+	if p, ok := tmpl.sourcePath(loc.name); ok {
+		name = p
+	}
+
+	data, err := fs.ReadFile(t.fsys, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template source %s: %w", name, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+
+	start := loc.line - 6
+	if start < 0 {
+		start = 0
+	}
+	end := loc.line + 5
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var buf bytes.Buffer
+	for i := start; i < end; i++ {
+		lineNo := i + 1
+		if lineNo == loc.line {
+			fmt.Fprintf(&buf, "<span class=\"hl\">%4d | %s</span>\n", lineNo, html.EscapeString(lines[i]))
+			continue
+		}
+		fmt.Fprintf(&buf, "%4d | %s\n", lineNo, html.EscapeString(lines[i]))
+	}
+
+	return buf.String(), nil
+}
+
+const devErrorPageHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>template error</title>
+<style>
+body { font-family: monospace; background: #1e1e1e; color: #ddd; padding: 2em; }
+h1 { color: #f55; }
+.message { color: #f99; }
+.source { background: #111; padding: 1em; overflow-x: auto; }
+.hl { background: #552222; display: inline-block; width: 100%; }
+.chain { color: #9cf; }
+</style>
+</head>
+<body>
+`
+
+const devErrorPageFooter = `</body>
+</html>
+`
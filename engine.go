@@ -0,0 +1,30 @@
+package templates
+
+import "github.com/wolfeidau/echo-views/engine"
+
+// FuncMap defines the template functions available to the configured
+// Engine, independent of which templating library it wraps.
+type FuncMap = engine.FuncMap
+
+// Engine parses template sources into an executable Compiled template,
+// decoupling ViewRenderer from any one templating library. See
+// engines/htmltmpl, engines/text, engines/pongo2, and engines/handlebars for
+// the bundled implementations.
+type Engine = engine.Engine
+
+// Compiled is a parsed template, ready to execute against data.
+type Compiled = engine.Compiled
+
+// NamedExecutor is the optional Compiled capability RenderFormat uses to
+// resolve a per-format layout within an already-parsed set. See
+// engine.NamedExecutor.
+type NamedExecutor = engine.NamedExecutor
+
+// WithEngine sets the template engine used to parse and execute views. It
+// defaults to engines/htmltmpl, matching the html/template-only behaviour
+// this package started with.
+func WithEngine(e Engine) Option {
+	return func(r *ViewRenderer) {
+		r.engine = e
+	}
+}
@@ -0,0 +1,48 @@
+// Package engine defines the pluggable template engine contract shared by
+// ViewRenderer and its engines/* implementations, so the root package has no
+// hard dependency on any one templating library.
+package engine
+
+import "io"
+
+// FuncMap defines the template functions available to an Engine,
+// independent of which templating library it wraps.
+type FuncMap map[string]any
+
+// Engine parses a set of named template sources into an executable Compiled
+// template.
+type Engine interface {
+	// Parse parses sources, keyed by file base name, and returns a Compiled
+	// template whose Execute runs the template named name.
+	Parse(name string, sources map[string][]byte, funcs FuncMap) (Compiled, error)
+}
+
+// Compiled is a parsed template, ready to execute against data.
+type Compiled interface {
+	Execute(w io.Writer, data any) error
+}
+
+// NamedExecutor is an optional capability of a Compiled template whose
+// Parse call combined several named sources into one set, such as
+// engines/htmltmpl and engines/text. It lets a caller execute a different
+// entry point than the one given to Parse, which RenderFormat uses to
+// resolve a per-format layout. Engines that compose templates via their own
+// include/extend syntax instead (engines/pongo2, engines/handlebars) have no
+// reason to implement it.
+type NamedExecutor interface {
+	// ExecuteNamed executes the template named name instead of the Compiled's
+	// own entry point. found is false when no template by that name exists.
+	ExecuteNamed(w io.Writer, name string, data any) (found bool, err error)
+}
+
+// EntryPointSelector is an optional Engine capability for engines that
+// compose layout, includes, and page themselves via their own extends or
+// partial syntax (engines/pongo2, engines/handlebars) rather than by
+// executing the layout as the outer template (engines/htmltmpl,
+// engines/text). The caller uses it to decide which of the registered
+// sources to hand Parse as its entry point name.
+type EntryPointSelector interface {
+	// PageIsEntryPoint reports whether Parse's entry point should be the
+	// page rather than the layout when a View has one configured.
+	PageIsEntryPoint() bool
+}
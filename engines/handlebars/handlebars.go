@@ -0,0 +1,70 @@
+// Package handlebars implements the templates.Engine interface using
+// github.com/aymerick/raymond, a Go port of Handlebars.js. Composition uses
+// raymond's own {{> partial}} syntax: every source other than the entry
+// template is registered as a partial keyed by its base name. A
+// layout-configured view's entry point is the page, which is expected to
+// wrap itself in its layout with a block partial, e.g.
+// "{{#> layout}}...{{/layout}}", the Handlebars.js equivalent of pongo2's
+// {% extends %}.
+package handlebars
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aymerick/raymond"
+	"github.com/wolfeidau/echo-views/engine"
+)
+
+// Engine parses templates using raymond.
+type Engine struct{}
+
+// New creates a raymond backed Engine.
+func New() *Engine {
+	return &Engine{}
+}
+
+// PageIsEntryPoint reports true, satisfying engine.EntryPointSelector: a
+// layout-configured View's entry point is the page, which wraps itself in
+// its layout via a block partial rather than the layout including the page.
+func (e *Engine) PageIsEntryPoint() bool {
+	return true
+}
+
+// Parse compiles name as the entry template, registers every other source
+// as a partial, and registers funcs as Handlebars helpers.
+func (e *Engine) Parse(name string, sources map[string][]byte, funcs engine.FuncMap) (engine.Compiled, error) {
+	tmpl, err := raymond.Parse(string(sources[name]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	for fname, data := range sources {
+		if fname == name {
+			continue
+		}
+		tmpl.RegisterPartial(fname, string(data))
+	}
+
+	for fname, fn := range funcs {
+		tmpl.RegisterHelper(fname, fn)
+	}
+
+	return &compiled{tmpl: tmpl}, nil
+}
+
+type compiled struct {
+	tmpl *raymond.Template
+}
+
+// Execute renders the compiled template.
+func (c *compiled) Execute(w io.Writer, data any) error {
+	out, err := c.tmpl.Exec(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, out)
+
+	return err
+}
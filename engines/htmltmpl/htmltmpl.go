@@ -0,0 +1,61 @@
+// Package htmltmpl implements the templates.Engine interface using the
+// standard library's html/template package. It is the default engine used
+// by templates.New when no WithEngine option is supplied.
+package htmltmpl
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/wolfeidau/echo-views/engine"
+)
+
+// Engine parses templates using html/template.
+type Engine struct{}
+
+// New creates a html/template backed Engine.
+func New() *Engine {
+	return &Engine{}
+}
+
+// Parse parses sources into a single *template.Template, with name as the
+// root, and every other source registered under its own base name so the
+// layout can combine them with {{template "name"}}.
+func (e *Engine) Parse(name string, sources map[string][]byte, funcs engine.FuncMap) (engine.Compiled, error) {
+	root := template.New(name).Funcs(template.FuncMap(funcs))
+
+	for fname, data := range sources {
+		var err error
+		if fname == name {
+			root, err = root.Parse(string(data))
+		} else {
+			_, err = root.New(fname).Parse(string(data))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", fname, err)
+		}
+	}
+
+	return &compiled{name: name, tmpl: root}, nil
+}
+
+type compiled struct {
+	name string
+	tmpl *template.Template
+}
+
+// Execute renders the entry template given to Parse.
+func (c *compiled) Execute(w io.Writer, data any) error {
+	return c.tmpl.ExecuteTemplate(w, c.name, data)
+}
+
+// ExecuteNamed renders name instead of the entry template given to Parse,
+// satisfying engine.NamedExecutor.
+func (c *compiled) ExecuteNamed(w io.Writer, name string, data any) (bool, error) {
+	if c.tmpl.Lookup(name) == nil {
+		return false, nil
+	}
+
+	return true, c.tmpl.ExecuteTemplate(w, name, data)
+}
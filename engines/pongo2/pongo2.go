@@ -0,0 +1,82 @@
+// Package pongo2 implements the templates.Engine interface using
+// github.com/flosch/pongo2/v6, a Django-style templating engine. Unlike the
+// html/template based engines, pongo2 composes templates itself via
+// {% extends %} and {% include %}, so the layout and page sources are
+// registered under their own base names and the page is expected to extend
+// its layout by that name.
+package pongo2
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/wolfeidau/echo-views/engine"
+)
+
+// Engine parses templates using pongo2.
+type Engine struct{}
+
+// New creates a pongo2 backed Engine.
+func New() *Engine {
+	return &Engine{}
+}
+
+// PageIsEntryPoint reports true, satisfying engine.EntryPointSelector: a
+// layout-configured View's entry point is the page, which pulls in its
+// layout itself via {% extends %}, rather than the layout.
+func (e *Engine) PageIsEntryPoint() bool {
+	return true
+}
+
+// Parse registers every source under its own base name with an in-memory
+// loader scoped to this call, then compiles name as the entry template.
+//
+// pongo2 registers filters and tags globally rather than per template set,
+// so funcs is not supported and is ignored; register custom filters with
+// pongo2.RegisterFilter before calling Parse instead.
+func (e *Engine) Parse(name string, sources map[string][]byte, funcs engine.FuncMap) (engine.Compiled, error) {
+	set := pongo2.NewSet("echo-views", &memLoader{sources: sources})
+
+	tmpl, err := set.FromFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	return &compiled{tmpl: tmpl}, nil
+}
+
+type compiled struct {
+	tmpl *pongo2.Template
+}
+
+// Execute renders the compiled template. data is exposed to the template as
+// pongo2.Context when it already is one, otherwise under the key "data".
+func (c *compiled) Execute(w io.Writer, data any) error {
+	ctx, ok := data.(pongo2.Context)
+	if !ok {
+		ctx = pongo2.Context{"data": data}
+	}
+
+	return c.tmpl.ExecuteWriter(ctx, w)
+}
+
+// memLoader is a pongo2.TemplateLoader backed by an in-memory set of sources
+// keyed by base name, scoped to a single Parse call.
+type memLoader struct {
+	sources map[string][]byte
+}
+
+func (l *memLoader) Abs(base, name string) string {
+	return name
+}
+
+func (l *memLoader) Get(path string) (io.Reader, error) {
+	data, ok := l.sources[path]
+	if !ok {
+		return nil, fmt.Errorf("template not found: %s", path)
+	}
+
+	return bytes.NewReader(data), nil
+}
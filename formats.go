@@ -0,0 +1,154 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Format describes a named output format that an existing view can be
+// rendered as, inspired by Hugo's output.Format. The same page template can
+// be registered once per format (e.g. "index.html" and "index.amp.html")
+// with its own layout, and served with the format's Content-Type.
+type Format struct {
+	// Name identifies the format, e.g. "html", "amp", "rss", "json".
+	Name string
+	// MediaType is the Content-Type set on the response.
+	MediaType string
+	// Suffix is inserted before the file extension to resolve the page
+	// template, e.g. "amp" resolves "index.html" to "index.amp.html".
+	Suffix string
+	// LayoutSuffix is inserted before the file extension to resolve the
+	// layout. Defaults to Suffix when empty.
+	LayoutSuffix string
+}
+
+// WithFormats registers the output formats available to RenderFormat, keyed
+// by their Name.
+func WithFormats(formats ...Format) Option {
+	return func(r *ViewRenderer) {
+		for _, f := range formats {
+			r.formats[f.Name] = f
+		}
+	}
+}
+
+// RenderFormat renders name as the named output format, falling back to the
+// plain template and layout when no format-specific variant is registered,
+// and sets the response Content-Type from the format's media type.
+func (t *ViewRenderer) RenderFormat(c Context, code int, name string, format string, data any) error {
+	f, ok := t.formats[format]
+	if !ok {
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+
+	pageName := name
+	if f.Suffix != "" {
+		if candidate := formatCandidate(name, f.Suffix); t.hasTemplate(candidate) {
+			pageName = candidate
+		}
+	}
+
+	tmpl, err := t.lookupTemplate(pageName)
+	if err != nil {
+		t.logger.ErrorCtx(c.Request().Context(), "failed to load template", err, map[string]any{"name": pageName, "format": format})
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := t.executeFormat(buf, tmpl, f, data); err != nil {
+		t.logger.ErrorCtx(c.Request().Context(), "failed to execute template", err, map[string]any{"name": tmpl.name, "layout": tmpl.layout, "format": format})
+		return err
+	}
+
+	return c.Blob(code, f.MediaType, buf.Bytes())
+}
+
+// executeFormat executes tmpl's compiled template, preferring a per-format
+// layout when the engine supports looking one up by name (see
+// engine.NamedExecutor) and one is registered under f's LayoutSuffix.
+func (t *ViewRenderer) executeFormat(buf *bytes.Buffer, tmpl *View, f Format, data any) error {
+	compiledTmpl := tmpl.template()
+
+	if tmpl.layout != "" {
+		layoutSuffix := f.LayoutSuffix
+		if layoutSuffix == "" {
+			layoutSuffix = f.Suffix
+		}
+
+		if ne, ok := compiledTmpl.(NamedExecutor); ok && layoutSuffix != "" {
+			candidate := formatCandidate(tmpl.layoutEntryName(), layoutSuffix)
+
+			found, err := ne.ExecuteNamed(buf, candidate, data)
+			if err != nil {
+				return err
+			}
+			if found {
+				return nil
+			}
+		}
+	}
+
+	return compiledTmpl.Execute(buf, data)
+}
+
+// hasTemplate reports whether name is a registered View.
+func (t *ViewRenderer) hasTemplate(name string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	_, ok := t.templates[name]
+
+	return ok
+}
+
+// formatCandidate inserts suffix before name's file extension, e.g.
+// formatCandidate("index.html", "amp") returns "index.amp.html".
+func formatCandidate(name, suffix string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	return fmt.Sprintf("%s.%s%s", base, suffix, ext)
+}
+
+// resolveLayoutEntry picks the format-less layout entry point out of
+// matches, the files a View's layout pattern expanded to. A plain layout
+// ("layout.html") has a single match and is returned as-is. A layout glob
+// that pulls per-format variants into one compiled set ("layout*.html" ->
+// layout.html, layout.amp.html, ...) instead returns whichever match isn't
+// itself a formatCandidate of another match for a known format suffix, so
+// RenderFormat can still resolve "layout.amp.html" from it by name.
+func resolveLayoutEntry(matches []string, formats map[string]Format) string {
+	if len(matches) == 1 {
+		return path.Base(matches[0])
+	}
+
+	suffixes := make(map[string]struct{}, len(formats)*2)
+	for _, f := range formats {
+		if f.Suffix != "" {
+			suffixes[f.Suffix] = struct{}{}
+		}
+		if f.LayoutSuffix != "" {
+			suffixes[f.LayoutSuffix] = struct{}{}
+		}
+	}
+
+	for _, m := range matches {
+		base := path.Base(m)
+		stem := strings.TrimSuffix(base, path.Ext(base))
+
+		if suffix := path.Ext(stem); suffix != "" {
+			if _, ok := suffixes[strings.TrimPrefix(suffix, ".")]; ok {
+				continue
+			}
+		}
+
+		return base
+	}
+
+	// every match looked like a per-format variant, so there's no
+	// unambiguous base: fall back to the first match in glob order.
+	return path.Base(matches[0])
+}
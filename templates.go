@@ -3,19 +3,29 @@ package templates
 import (
 	"bytes"
 	"fmt"
-	"html/template"
 	"io"
 	"io/fs"
 	"net/http"
 	"path"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wolfeidau/echo-views/engine"
+	"github.com/wolfeidau/echo-views/engines/htmltmpl"
 )
 
 type Context interface {
 	// Request returns `*http.Request`.
 	Request() *http.Request
+	// Response returns the response, for setting headers such as ETag and
+	// Last-Modified directly.
+	Response() *echo.Response
 	// HTMLBlob sends an HTTP blob response with status code.
 	HTMLBlob(code int, b []byte) error
+	// Blob sends a blob response with status code and content type.
+	Blob(code int, contentType string, b []byte) error
 	// NoContent sends a response with no body and a status code.
 	NoContent(code int) error
 }
@@ -25,15 +35,115 @@ type View struct {
 	layout   string
 	name     string
 	includes string
-	template *template.Template
+
+	// compiled is swapped atomically by compileTemplate so an in-flight
+	// Render can keep using the template it looked up even while
+	// auto-reload or Watch recompiles this View in the background.
+	compiled atomic.Pointer[compiledBox]
+
+	// sourcePaths maps each source's base name, the key html/template uses
+	// internally and therefore the key that shows up in parse/exec errors,
+	// back to its real path in fsys. It's set before Parse runs so dev error
+	// pages can still read source context for a template under a
+	// subdirectory (pages/, includes/) when Parse itself fails.
+	sourcePaths atomic.Pointer[map[string]string]
+
+	// layoutEntry is the concrete, format-less layout file name compileTemplate
+	// resolved tmpl.layout to, e.g. "layout.html" when layout is a glob like
+	// "layout*.html" that pulls several per-format variants (layout.html,
+	// layout.amp.html, ...) into one compiled set. executeFormat uses it,
+	// rather than tmpl.layout itself, as the base for per-format layout
+	// lookups.
+	layoutEntry atomic.Pointer[string]
+
+	// compileErr holds the error from the View's most recent compileTemplate
+	// attempt, or nil after a successful one. It lets Render still show the
+	// dev error page for a template that failed to compile during Add* and
+	// was therefore never reachable via lookupTemplate's normal path.
+	compileErr atomic.Pointer[error]
+}
+
+// compiledBox lets View swap its compiled template with a single atomic
+// pointer store, since Compiled is an interface and atomic.Value requires a
+// consistent concrete type across stores.
+type compiledBox struct {
+	tmpl Compiled
+}
+
+// setCompiled atomically replaces the View's compiled template.
+func (v *View) setCompiled(c Compiled) {
+	v.compiled.Store(&compiledBox{tmpl: c})
+}
+
+// template returns the View's current compiled template.
+func (v *View) template() Compiled {
+	box := v.compiled.Load()
+	if box == nil {
+		return nil
+	}
+	return box.tmpl
+}
+
+// setSourcePaths atomically records the base-name-to-path mapping used for
+// the View's most recent compile attempt.
+func (v *View) setSourcePaths(paths map[string]string) {
+	v.sourcePaths.Store(&paths)
+}
+
+// setLayoutEntry atomically records the resolved, format-less layout entry
+// name used for the View's most recent compile attempt.
+func (v *View) setLayoutEntry(name string) {
+	v.layoutEntry.Store(&name)
+}
+
+// layoutEntryName returns the layout entry name set by setLayoutEntry, or
+// "" if the View has no layout.
+func (v *View) layoutEntryName() string {
+	p := v.layoutEntry.Load()
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// setCompileErr atomically records the outcome of the View's most recent
+// compileTemplate attempt.
+func (v *View) setCompileErr(err error) {
+	v.compileErr.Store(&err)
+}
+
+// compileError returns the error recorded by setCompileErr, or nil if the
+// View's most recent compile attempt succeeded.
+func (v *View) compileError() error {
+	p := v.compileErr.Load()
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// sourcePath returns the real fsys path registered for base, if any.
+func (v *View) sourcePath(base string) (string, bool) {
+	paths := v.sourcePaths.Load()
+	if paths == nil {
+		return "", false
+	}
+	p, ok := (*paths)[base]
+	return p, ok
 }
 
 // ViewRenderer contains the template renderer state.
 type ViewRenderer struct {
 	fsys          fs.FS
 	autoReload    bool
+	devErrors     bool
+	watchRoot     string
+	mu            sync.RWMutex // guards templates
 	templates     map[string]*View
-	templateFuncs template.FuncMap
+	templateFuncs FuncMap
+	engine        Engine
+	formats       map[string]Format
+	cache         *renderCache
 	logger        Logger
 }
 
@@ -55,12 +165,22 @@ func WithAutoReload(enabled bool) Option {
 }
 
 // WithFuncs sets the template functions to use.
-func WithFuncs(funcs template.FuncMap) Option {
+func WithFuncs(funcs FuncMap) Option {
 	return func(r *ViewRenderer) {
 		r.templateFuncs = funcs
 	}
 }
 
+// WithDevErrors enables rendering parse and execute errors as a browser-friendly
+// HTML page with source context, instead of a bare 500 response. This is
+// intended for development only and should not be enabled in production, as
+// it exposes template source to the client.
+func WithDevErrors(enabled bool) Option {
+	return func(r *ViewRenderer) {
+		r.devErrors = enabled
+	}
+}
+
 // WithLogger sets the logger to use.
 func WithLogger(logger Logger) Option {
 	return func(r *ViewRenderer) {
@@ -72,7 +192,9 @@ func WithLogger(logger Logger) Option {
 func New(opts ...Option) *ViewRenderer {
 	r := &ViewRenderer{
 		templates:     make(map[string]*View),
-		templateFuncs: template.FuncMap{},
+		templateFuncs: FuncMap{},
+		engine:        htmltmpl.New(),
+		formats:       make(map[string]Format),
 		logger:        &noopLogger{},
 	}
 
@@ -158,19 +280,39 @@ func (t *ViewRenderer) Render(w io.Writer, name string, data interface{}, c Cont
 	tmpl, err := t.lookupTemplate(name)
 	if err != nil {
 		t.logger.ErrorCtx(c.Request().Context(), "failed to load template", err, map[string]any{"name": name})
+		if t.devErrors {
+			// tmpl is non-nil even on a recompile failure (see lookupTemplate),
+			// carrying the sourcePaths compileTemplate recorded before Parse
+			// failed, so devErrorPage can still resolve subdirectory sources.
+			// It's only nil when name was never registered at all.
+			view := tmpl
+			if view == nil {
+				view = &View{name: name}
+			}
+			_, _ = w.Write(t.devErrorPage(view, "parse", err))
+			return nil
+		}
 		return c.NoContent(http.StatusInternalServerError)
 	}
 
-	// use the name of the template, or layout if it exists
-	execName := path.Base(tmpl.name)
-	if tmpl.layout != "" {
-		execName = path.Base(tmpl.layout)
-	}
+	// rendered into a scratch buffer, not w directly: html/template can write
+	// partial output before failing partway through Execute, and flushing
+	// that straight to w would leave it trailing the dev error page below.
+	buf := new(bytes.Buffer)
 
-	err = tmpl.template.ExecuteTemplate(w, execName, data)
+	err = tmpl.template().Execute(buf, data)
 	if err != nil {
 		t.logger.ErrorCtx(c.Request().Context(), "failed to execute template", err, map[string]any{"name": tmpl.name, "layout": tmpl.layout})
 
+		if t.devErrors {
+			_, _ = w.Write(t.devErrorPage(tmpl, "execute", err))
+			return nil
+		}
+
+		return err
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
 		return err
 	}
 
@@ -189,35 +331,70 @@ func (t *ViewRenderer) RenderToHTMLBlob(c Context, code int, name string, data a
 }
 
 func (t *ViewRenderer) lookupTemplate(name string) (*View, error) {
+	t.mu.RLock()
 	tmpl, ok := t.templates[name]
+	t.mu.RUnlock()
+
 	if !ok {
 		return nil, fmt.Errorf("template not found: %s", name)
 	}
 
 	if !t.autoReload {
+		// surfaces a registration-time (Add*) compile failure that would
+		// otherwise only be visible to the caller of Add*, not to Render
+		if cerr := tmpl.compileError(); cerr != nil {
+			return tmpl, cerr
+		}
 		return tmpl, nil
 	}
 
+	// recompiling re-takes the lock for writing, so it happens outside the
+	// read lock above. tmpl is still returned on error: compileTemplate
+	// records sourcePaths before it calls Parse, so the caller can still use
+	// tmpl to resolve a parse error's source even though recompilation
+	// failed.
 	err := t.compileTemplate(tmpl)
-	if err != nil {
-		return nil, err
-	}
 
-	return tmpl, nil
+	return tmpl, err
 }
 
 func (t *ViewRenderer) compileTemplate(tmpl *View) (err error) {
 	templateName := path.Base(tmpl.name)
 
+	defer func() {
+		tmpl.setCompileErr(err)
+
+		if err != nil {
+			// still register the View under templateName, broken compiled
+			// template and all, so a later Render(templateName) shows the dev
+			// error page for this exact failure instead of "template not
+			// found" - even when it was WithDevErrors' most common failure
+			// moment, the first Add*, that registered it.
+			t.mu.Lock()
+			t.templates[templateName] = tmpl
+			t.mu.Unlock()
+		}
+	}()
+
 	t.logger.Debug("register template", map[string]any{"name": tmpl.name, "layout": tmpl.layout, "includes": tmpl.includes})
 	//
 	// the list of patterns varies depending on whether the template uses a layout or includes
 	//
 	patterns := make([]string, 0)
 
-	// add the layout if it exists
+	// add the layout if it exists, and resolve the concrete, format-less
+	// layout file name to use as the entry point: tmpl.layout may be a glob
+	// like "layout*.html" that pulls several per-format variants
+	// (layout.html, layout.amp.html, ...) into one compiled set.
+	var layoutEntry string
 	if tmpl.layout != "" {
 		patterns = append(patterns, tmpl.layout)
+
+		layoutMatches, err := readFileNames(t.fsys, tmpl.layout)
+		if err != nil {
+			return fmt.Errorf("failed to list using file pattern: %w", err)
+		}
+		layoutEntry = resolveLayoutEntry(layoutMatches, t.formats)
 	}
 
 	// then add the includes if they exist
@@ -228,12 +405,42 @@ func (t *ViewRenderer) compileTemplate(tmpl *View) (err error) {
 	// finally add the template itself
 	patterns = append(patterns, tmpl.name)
 
-	tmpl.template, err = template.New(templateName).Funcs(t.templateFuncs).ParseFS(t.fsys, patterns...)
+	sources, paths, err := readFileSources(t.fsys, patterns...)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", tmpl.name, err)
+	}
+
+	// recorded before Parse runs, so devErrorPage can still resolve source
+	// paths for a parse error below
+	tmpl.setSourcePaths(paths)
+	tmpl.setLayoutEntry(layoutEntry)
+
+	// the engine executes the layout if one is configured, otherwise the
+	// template itself. Engines that compose layout and page themselves via
+	// their own extends/partial syntax (see engine.EntryPointSelector) want
+	// the page as their entry point instead.
+	execName := templateName
+	if tmpl.layout != "" {
+		execName = layoutEntry
+		if sel, ok := t.engine.(engine.EntryPointSelector); ok && sel.PageIsEntryPoint() {
+			execName = templateName
+		}
+	}
+
+	parsed, err := t.engine.Parse(execName, sources, t.templateFuncs)
 	if err != nil {
 		return fmt.Errorf("failed to parse template %s: %w", tmpl.name, err)
 	}
 
+	tmpl.setCompiled(parsed)
+
+	t.mu.Lock()
 	t.templates[templateName] = tmpl
+	t.mu.Unlock()
+
+	if t.cache != nil {
+		t.cache.invalidate(templateName)
+	}
 
 	return nil
 }
@@ -255,3 +462,29 @@ func readFileNames(fsys fs.FS, patterns ...string) ([]string, error) {
 
 	return filenames, nil
 }
+
+// readFileSources expands patterns and reads each matched file, keyed by its
+// base name, for handing to an Engine. It also returns the base name to real
+// fsys path mapping, since a template's base name alone doesn't say which
+// directory (pages/, includes/, ...) it was read from.
+func readFileSources(fsys fs.FS, patterns ...string) (map[string][]byte, map[string]string, error) {
+	filenames, err := readFileNames(fsys, patterns...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := make(map[string][]byte, len(filenames))
+	paths := make(map[string]string, len(filenames))
+
+	for _, f := range filenames {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", f, err)
+		}
+		base := path.Base(f)
+		sources[base] = data
+		paths[base] = f
+	}
+
+	return sources, paths, nil
+}
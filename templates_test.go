@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
-	"text/template"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 	templates "github.com/wolfeidau/echo-views"
+	"github.com/wolfeidau/echo-views/engines/text"
 	"github.com/wolfeidau/echo-views/test/views"
 )
 
@@ -26,7 +29,7 @@ func Test_CustomFuncs_AddWithLayout(t *testing.T) {
 	render := templates.New(
 		templates.WithLogger(&testLogger{}),
 		templates.WithFS(views.Content),
-		templates.WithFuncs(template.FuncMap{
+		templates.WithFuncs(templates.FuncMap{
 			"getTime2": func() string {
 				return time.Now().Format("15:04:05")
 			},
@@ -55,7 +58,7 @@ func Test_AddWithLayout(t *testing.T) {
 
 	render := templates.New(
 		templates.WithLogger(&testLogger{}),
-		templates.WithFuncs(template.FuncMap{
+		templates.WithFuncs(templates.FuncMap{
 			"getTime": func() string {
 				return time.Now().Format("15:04:05")
 			},
@@ -87,7 +90,7 @@ func Test_AddWithLayoutAndIncludes(t *testing.T) {
 	render := templates.New(
 		templates.WithLogger(&testLogger{}),
 		templates.WithFS(views.Content),
-		templates.WithFuncs(template.FuncMap{
+		templates.WithFuncs(templates.FuncMap{
 			"getTime": func() string {
 				return time.Now().Format("15:04:05")
 			},
@@ -130,6 +133,280 @@ func Test_Add(t *testing.T) {
 	assert.Equal(200, rec.Result().StatusCode)
 }
 
+func Test_DevErrors_MissingTemplate(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(
+		templates.WithFS(views.Content),
+		templates.WithDevErrors(true),
+	)
+
+	output := bytes.NewBufferString("")
+
+	c := e.NewContext(req, rec)
+
+	err := render.Render(output, "missing.html", nil, c)
+	assert.NoError(err)
+	assert.Contains(output.String(), "template not found")
+}
+
+func Test_DevErrors_ParseFault(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "pages"), 0o755))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "pages", "broken.html"), []byte("one\ntwo\n{{.Three}}\n"), 0o644))
+
+	render := templates.New(
+		templates.WithFS(os.DirFS(dir)),
+		templates.WithAutoReload(true),
+		templates.WithDevErrors(true),
+	)
+
+	assert.NoError(render.Add("pages/broken.html"))
+
+	// introduce a parse fault and let auto-reload pick it up on Render,
+	// rather than on Add, so it's the registered *View, not a throwaway one,
+	// that devErrorPage has to resolve source context for.
+	assert.NoError(os.WriteFile(filepath.Join(dir, "pages", "broken.html"), []byte("one\ntwo\n{{.Three\nfour\n"), 0o644))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+
+	err := render.Render(output, "broken.html", nil, c)
+	assert.NoError(err)
+
+	assert.Contains(output.String(), "broken.html")
+	assert.Contains(output.String(), `class="hl"`)
+	assert.Contains(output.String(), "{{.Three")
+}
+
+func Test_Render_ConcurrentWithAdd(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(
+		templates.WithFS(views.Content),
+		templates.WithAutoReload(true),
+		templates.WithFuncs(templates.FuncMap{
+			"getTime2": func() string {
+				return time.Now().Format("15:04:05")
+			},
+		}),
+	)
+
+	err := render.AddWithLayout("layout2.html", "pages2/*.html")
+	assert.NoError(err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			_ = render.Render(bytes.NewBufferString(""), "index2.html", nil, c)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_ = render.AddWithLayout("layout2.html", "pages2/*.html")
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_RenderFormat_FallsBackToPlainTemplate(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(
+		templates.WithFS(views.Content),
+		templates.WithFuncs(templates.FuncMap{
+			"getTime": func() string {
+				return time.Now().Format("15:04:05")
+			},
+		}),
+		templates.WithFormats(templates.Format{
+			Name:      "amp",
+			MediaType: "text/html; amp",
+			Suffix:    "amp",
+		}),
+	)
+
+	err := render.AddWithLayoutAndIncludes("layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	err = render.RenderFormat(c, http.StatusOK, "index.html", "amp", nil)
+	assert.NoError(err)
+	assert.Equal("text/html; amp", rec.Header().Get("Content-Type"))
+}
+
+func Test_RenderFormat_PerFormatLayout(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(
+		templates.WithFS(views.Content),
+		templates.WithFuncs(templates.FuncMap{
+			"getTime": func() string {
+				return time.Now().Format("15:04:05")
+			},
+		}),
+		templates.WithFormats(templates.Format{
+			Name:      "amp",
+			MediaType: "text/html; amp",
+			Suffix:    "amp",
+		}),
+	)
+
+	// layout*.html pulls both layout.html and layout.amp.html into the same
+	// compiled set, so RenderFormat can pick layout.amp.html by name.
+	err := render.AddWithLayout("layout*.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	err = render.RenderFormat(c, http.StatusOK, "index.html", "amp", nil)
+	assert.NoError(err)
+	assert.Equal("text/html; amp", rec.Header().Get("Content-Type"))
+	assert.Contains(output(rec), "amp-layout")
+}
+
+func output(rec *httptest.ResponseRecorder) string {
+	return rec.Body.String()
+}
+
+func Test_WithEngine_Text(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(
+		templates.WithFS(views.Content),
+		templates.WithEngine(text.New()),
+	)
+
+	err := render.Add("fragments/*.html")
+	assert.NoError(err)
+
+	output := bytes.NewBufferString("")
+
+	c := e.NewContext(req, rec)
+
+	err = render.Render(output, "data.html", nil, c)
+	assert.NoError(err)
+
+	assert.Equal("data", output.String())
+}
+
+func Test_RenderCached_NotModified(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+
+	render := templates.New(
+		templates.WithFS(views.Content),
+		templates.WithRenderCache(1<<20),
+	)
+
+	err := render.Add("fragments/*.html")
+	assert.NoError(err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err = render.RenderCached(c, http.StatusOK, "data.html", "v1", nil)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(etag)
+
+	req2 := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req2.Header.Set("If-None-Match", etag)
+	rec2 := httptest.NewRecorder()
+	c2 := e.NewContext(req2, rec2)
+
+	err = render.RenderCached(c2, http.StatusOK, "data.html", "v1", nil)
+	assert.NoError(err)
+	assert.Equal(http.StatusNotModified, rec2.Result().StatusCode)
+	assert.Equal(etag, rec2.Header().Get("ETag"))
+	assert.NotEmpty(rec2.Header().Get("Last-Modified"))
+}
+
+func Test_Watch_DebouncesPerFile(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "pages"), 0o755))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "layout.html"), []byte(`{{block "content" .}}{{end}}`), 0o644))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "pages", "a.html"), []byte(`{{define "content"}}a1{{end}}`), 0o644))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "pages", "b.html"), []byte(`{{define "content"}}b1{{end}}`), 0o644))
+
+	render := templates.New(
+		templates.WithFS(os.DirFS(dir)),
+		templates.WithWatch(dir),
+	)
+
+	assert.NoError(render.AddWithLayout("layout.html", "pages/*.html"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = render.Watch(ctx)
+	}()
+
+	// give the watcher time to register its directories before writing
+	time.Sleep(100 * time.Millisecond)
+
+	// two different files changed within the same debounce window - both
+	// must still be recompiled, not just the most recently written one
+	assert.NoError(os.WriteFile(filepath.Join(dir, "pages", "a.html"), []byte(`{{define "content"}}a2{{end}}`), 0o644))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "pages", "b.html"), []byte(`{{define "content"}}b2{{end}}`), 0o644))
+
+	assert.Eventually(func() bool {
+		return renderOutput(render, "a.html") == "a2" && renderOutput(render, "b.html") == "b2"
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func renderOutput(render *templates.ViewRenderer, name string) string {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	out := bytes.NewBufferString("")
+	_ = render.Render(out, name, nil, c)
+
+	return out.String()
+}
+
 type testLogger struct{}
 
 func (l *testLogger) DebugCtx(ctx context.Context, msg string, fields map[string]any) {
@@ -0,0 +1,159 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bep/debounce"
+	"github.com/fsnotify/fsnotify"
+)
+
+// WithWatch sets the root directory to watch for template changes once Watch
+// is started. rootDir must be the real directory backing the ViewRenderer's
+// fs.FS, since fsnotify only works against the local filesystem.
+func WithWatch(rootDir string) Option {
+	return func(r *ViewRenderer) {
+		r.watchRoot = rootDir
+	}
+}
+
+// Watch starts an fsnotify watcher over the directories backing the
+// registered view patterns, recompiling only the affected View entries when
+// their files are written, created, or renamed. Each file is debounced by
+// 100ms independently, so a burst of writes to the same file from an editor
+// or build tool triggers a single recompile, while unrelated files changed in
+// the same burst (e.g. a layout and a page saved together) each still get
+// recompiled. Recompilation happens in the debouncer's timer goroutines, off
+// the request path, guarded by an internal mutex so Render stays safe to call
+// concurrently.
+// Watch blocks until ctx is cancelled.
+func (t *ViewRenderer) Watch(ctx context.Context) error {
+	if t.watchRoot == "" {
+		return fmt.Errorf("watch: no root directory configured, use WithWatch")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, dir := range t.watchedDirs() {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	var debounceMu sync.Mutex
+	debouncers := make(map[string]func(func()))
+
+	debounceFile := func(name string) func(func()) {
+		debounceMu.Lock()
+		defer debounceMu.Unlock()
+
+		d, ok := debouncers[name]
+		if !ok {
+			d = debounce.New(100 * time.Millisecond)
+			debouncers[name] = d
+		}
+
+		return d
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			name := event.Name
+			debounceFile(name)(func() {
+				t.reloadFile(name)
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			t.logger.Debug("watch error", map[string]any{"error": err.Error()})
+		}
+	}
+}
+
+// watchedDirs returns the set of directories, rooted at watchRoot, backing
+// the files referenced by currently registered templates.
+func (t *ViewRenderer) watchedDirs() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	var dirs []string
+
+	for _, tmpl := range t.templates {
+		for _, f := range []string{tmpl.layout, tmpl.includes, tmpl.name} {
+			if f == "" {
+				continue
+			}
+
+			dir := filepath.Join(t.watchRoot, filepath.Dir(f))
+			if _, ok := seen[dir]; ok {
+				continue
+			}
+			seen[dir] = struct{}{}
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// reloadFile recompiles any View whose layout, includes, or page pattern
+// matches the changed file. compileTemplate takes its own write lock, so the
+// map is only read-locked here while collecting the affected views.
+func (t *ViewRenderer) reloadFile(name string) {
+	t.mu.RLock()
+	affected := make([]*View, 0, len(t.templates))
+	for _, tmpl := range t.templates {
+		if templateTouchesFile(tmpl, name) {
+			affected = append(affected, tmpl)
+		}
+	}
+	t.mu.RUnlock()
+
+	for _, tmpl := range affected {
+		if err := t.compileTemplate(tmpl); err != nil {
+			t.logger.Debug("failed to recompile template after change", map[string]any{"name": tmpl.name, "file": name, "error": err.Error()})
+		}
+	}
+}
+
+func templateTouchesFile(tmpl *View, name string) bool {
+	base := filepath.Base(name)
+
+	// tmpl.name is already a concrete file (readFileNames expanded it when
+	// it was registered), so compare it directly.
+	if tmpl.name != "" && filepath.Base(tmpl.name) == base {
+		return true
+	}
+
+	// tmpl.layout and tmpl.includes are the patterns passed to AddWithLayout
+	// / AddWithLayoutAndIncludes verbatim, and may still be globs (e.g.
+	// "includes/*.html", "layout*.html"), so match rather than compare.
+	for _, pattern := range []string{tmpl.layout, tmpl.includes} {
+		if pattern == "" {
+			continue
+		}
+		if ok, _ := filepath.Match(filepath.Base(pattern), base); ok {
+			return true
+		}
+	}
+
+	return false
+}